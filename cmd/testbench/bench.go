@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	testdb "go.keploy.io/server/v2/pkg/platform/yaml/testdb"
+	"go.uber.org/zap"
+)
+
+// latencyStat summarizes a sample of request latencies (HTTPResp.Timestamp
+// minus HTTPReq.Timestamp) the way a microbenchmark report would.
+type latencyStat struct {
+	Count  int
+	Mean   float64
+	Median float64
+	P95    float64
+	P99    float64
+	Stdev  float64
+}
+
+// computeLatencyStats expects samples in seconds.
+func computeLatencyStats(samples []float64) latencyStat {
+	if len(samples) == 0 {
+		return latencyStat{}
+	}
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, s := range sorted {
+		sum += s
+	}
+	mean := sum / float64(len(sorted))
+
+	var sqDiff float64
+	for _, s := range sorted {
+		d := s - mean
+		sqDiff += d * d
+	}
+	stdev := 0.0
+	if len(sorted) > 1 {
+		stdev = math.Sqrt(sqDiff / float64(len(sorted)-1))
+	}
+
+	return latencyStat{
+		Count:  len(sorted),
+		Mean:   mean,
+		Median: percentile(sorted, 50),
+		P95:    percentile(sorted, 95),
+		P99:    percentile(sorted, 99),
+		Stdev:  stdev,
+	}
+}
+
+// percentile expects sorted to already be sorted ascending.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := (p / 100) * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + frac*(sorted[hi]-sorted[lo])
+}
+
+// welchTTest performs a two-sample Welch's t-test and returns the t
+// statistic and an approximate two-tailed p-value, computed from the
+// Satterthwaite-approximated degrees of freedom via a normal approximation
+// (valid for the df encountered in latency samples, which are rarely small).
+func welchTTest(a, b []float64) (t, p float64) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, 1
+	}
+
+	statsA := computeLatencyStats(a)
+	statsB := computeLatencyStats(b)
+
+	varA := statsA.Stdev * statsA.Stdev
+	varB := statsB.Stdev * statsB.Stdev
+
+	seA := varA / float64(len(a))
+	seB := varB / float64(len(b))
+	se := math.Sqrt(seA + seB)
+	if se == 0 {
+		return 0, 1
+	}
+
+	t = (statsB.Mean - statsA.Mean) / se
+	p = 2 * (1 - normalCDF(math.Abs(t)))
+	return t, p
+}
+
+// normalCDF is the standard normal cumulative distribution function.
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// endpointBench is the latency comparison for every testcase sharing a
+// method+URL pair within a session.
+type endpointBench struct {
+	Session     string
+	Endpoint    string
+	PreRecorded latencyStat
+	TestBench   latencyStat
+	DeltaMean   float64
+	PValue      float64
+	Regressed   bool
+}
+
+// runBench computes per-session and per-endpoint latency deltas between the
+// pre-recorded and test-bench timestamps, prints them as a table, optionally
+// writes them to a CSV for trend tracking, and reports whether any endpoint's
+// p95 delta breaches regressionThreshold (a fraction, e.g. 0.2 for 20%).
+// regressionThreshold of nil disables the check entirely; a threshold of 0
+// fails on any regression at all.
+func runBench(ctx context.Context, logger *zap.Logger, db1, db2 *testdb.TestYaml, sessions []string, regressionThreshold *float64, csvPath string) (regressed bool, err error) {
+	var results []endpointBench
+
+	for _, session := range sessions {
+		readTcs1, err := db1.GetTestCases(ctx, session)
+		if err != nil {
+			return false, fmt.Errorf("failed to get pre-recorded test cases for session %s: %w", session, err)
+		}
+		readTcs2, err := db2.GetTestCases(ctx, session)
+		if err != nil {
+			return false, fmt.Errorf("failed to get test-bench test cases for session %s: %w", session, err)
+		}
+
+		sort.Slice(readTcs1, func(i, j int) bool { return readTcs1[i].Name < readTcs1[j].Name })
+		sort.Slice(readTcs2, func(i, j int) bool { return readTcs2[i].Name < readTcs2[j].Name })
+
+		if len(readTcs1) != len(readTcs2) {
+			logger.Error("number of test cases in both test sets are not equal", zap.Int("pre-recorded", len(readTcs1)), zap.Int("test-bench", len(readTcs2)))
+			return false, fmt.Errorf("session %s: pre-recorded has %d test cases, test-bench has %d", session, len(readTcs1), len(readTcs2))
+		}
+
+		byEndpoint := map[string][2][]float64{}
+		for i := range readTcs1 {
+			endpoint := fmt.Sprintf("%s %s", readTcs1[i].HTTPReq.Method, readTcs1[i].HTTPReq.URL)
+			preLatency := readTcs1[i].HTTPResp.Timestamp.Sub(readTcs1[i].HTTPReq.Timestamp).Seconds()
+			benchLatency := readTcs2[i].HTTPResp.Timestamp.Sub(readTcs2[i].HTTPReq.Timestamp).Seconds()
+
+			pair := byEndpoint[endpoint]
+			pair[0] = append(pair[0], preLatency)
+			pair[1] = append(pair[1], benchLatency)
+			byEndpoint[endpoint] = pair
+		}
+
+		endpoints := make([]string, 0, len(byEndpoint))
+		for endpoint := range byEndpoint {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+
+		for _, endpoint := range endpoints {
+			pair := byEndpoint[endpoint]
+			preStats := computeLatencyStats(pair[0])
+			benchStats := computeLatencyStats(pair[1])
+			_, pValue := welchTTest(pair[0], pair[1])
+
+			eb := endpointBench{
+				Session:     session,
+				Endpoint:    endpoint,
+				PreRecorded: preStats,
+				TestBench:   benchStats,
+				DeltaMean:   benchStats.Mean - preStats.Mean,
+				PValue:      pValue,
+			}
+
+			if regressionThreshold != nil && preStats.P95 > 0 {
+				p95Delta := (benchStats.P95 - preStats.P95) / preStats.P95
+				eb.Regressed = p95Delta > *regressionThreshold
+			}
+			if eb.Regressed {
+				regressed = true
+			}
+
+			results = append(results, eb)
+		}
+	}
+
+	printBenchTable(results)
+
+	if csvPath != "" {
+		if err := writeBenchCSV(results, csvPath); err != nil {
+			return regressed, fmt.Errorf("failed to write bench CSV: %w", err)
+		}
+	}
+
+	return regressed, nil
+}
+
+func printBenchTable(results []endpointBench) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "SESSION\tENDPOINT\tCOUNT\tPRE-MEAN(s)\tBENCH-MEAN(s)\tDELTA(s)\tPRE-P95(s)\tBENCH-P95(s)\tP-VALUE\tREGRESSED")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.6f\t%.6f\t%.6f\t%.6f\t%.6f\t%.4f\t%v\n",
+			r.Session, r.Endpoint, r.PreRecorded.Count, r.PreRecorded.Mean, r.TestBench.Mean, r.DeltaMean,
+			r.PreRecorded.P95, r.TestBench.P95, r.PValue, r.Regressed)
+	}
+	_ = w.Flush()
+}
+
+func writeBenchCSV(results []endpointBench, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	header := []string{"session", "endpoint", "count", "pre_mean", "bench_mean", "delta_mean", "pre_p95", "bench_p95", "pre_p99", "bench_p99", "p_value", "regressed"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		row := []string{
+			r.Session,
+			r.Endpoint,
+			strconv.Itoa(r.PreRecorded.Count),
+			strconv.FormatFloat(r.PreRecorded.Mean, 'f', -1, 64),
+			strconv.FormatFloat(r.TestBench.Mean, 'f', -1, 64),
+			strconv.FormatFloat(r.DeltaMean, 'f', -1, 64),
+			strconv.FormatFloat(r.PreRecorded.P95, 'f', -1, 64),
+			strconv.FormatFloat(r.TestBench.P95, 'f', -1, 64),
+			strconv.FormatFloat(r.PreRecorded.P99, 'f', -1, 64),
+			strconv.FormatFloat(r.TestBench.P99, 'f', -1, 64),
+			strconv.FormatFloat(r.PValue, 'f', -1, 64),
+			strconv.FormatBool(r.Regressed),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRegressionThreshold parses flags like "20%" or "0.2" into a fraction.
+// It returns nil when s is empty, so a caller can tell "no threshold was
+// given" apart from "a threshold of 0 was given" (i.e. fail on any
+// regression at all).
+func parseRegressionThreshold(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --regression-threshold %q: %w", s, err)
+		}
+		v /= 100
+		return &v, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --regression-threshold %q: %w", s, err)
+	}
+	return &v, nil
+}
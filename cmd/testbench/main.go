@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+
+	"go.keploy.io/server/v2/pkg"
+	testdb "go.keploy.io/server/v2/pkg/platform/yaml/testdb"
+	"go.uber.org/zap"
+
+	"testbench/pkg/testbench"
+)
+
+func main() {
+
+	// Define the flags
+	testAssert := flag.Bool("test-assert", false, "Enable test assertions")
+	mockAssert := flag.Bool("mock-assert", false, "Enable mock assertion preparation")
+	bench := flag.Bool("bench", false, "Enable latency-delta microbenchmark mode")
+
+	preRecPath := flag.String("preRecPath", ".", "Path to pre-recorded test cases & mocks")
+	testBenchPath := flag.String("testBenchPath", ".", "Path to testbench test cases & mocks")
+	configPath := flag.String("configPath", ".", "Path to configuration file")
+
+	reportFormatFlag := flag.String("report-format", string(reportFormatText), "Format of the test-assert report: text, junit or json")
+	reportOut := flag.String("report-out", "", "Path to write the test-assert report to (defaults to stdout for junit/json)")
+
+	regressionThresholdFlag := flag.String("regression-threshold", "", "Fail --bench if a endpoint's p95 latency delta exceeds this (e.g. 20%)")
+	benchCSV := flag.String("bench-csv", "", "Path to write the --bench latency report to as CSV, for trend tracking")
+
+	concurrency := flag.Int("concurrency", runtime.GOMAXPROCS(0), "Number of sessions to compare concurrently")
+
+	// Parse the flags
+	flag.Parse()
+
+	modesSelected := 0
+	for _, selected := range []bool{*testAssert, *mockAssert, *bench} {
+		if selected {
+			modesSelected++
+		}
+	}
+	if modesSelected != 1 {
+		panic("Please provide exactly one of -test-assert, -mock-assert or -bench flag")
+	}
+
+	if *concurrency < 1 {
+		panic("--concurrency must be at least 1")
+	}
+
+	format := reportFormat(*reportFormatFlag)
+	switch format {
+	case reportFormatText, reportFormatJUnit, reportFormatJSON:
+	default:
+		panic(fmt.Sprintf("unsupported --report-format %q, expected text, junit or json", *reportFormatFlag))
+	}
+
+	regressionThreshold, err := parseRegressionThreshold(*regressionThresholdFlag)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	// Create a new zap logger (info, debug, warn, error, fatal, panic)
+	logger, err := NewDevelopmentLogger("info")
+	if err != nil {
+		panic("failed to create logger")
+	}
+	syncErr := logger.Sync()
+	if syncErr != nil {
+		logger.Debug("failed to sync logger", zap.Error(syncErr))
+	}
+
+	*preRecPath, err = getAbsolutePath(*preRecPath)
+	if err != nil {
+		logger.Error("failed to get absolute path", zap.String("path", *preRecPath), zap.Error(err))
+		return
+	}
+
+	*preRecPath = filepath.Join(*preRecPath, "keploy")
+
+	*testBenchPath, err = getAbsolutePath(*testBenchPath)
+	if err != nil {
+		logger.Error("failed to get absolute path", zap.String("path", *testBenchPath), zap.Error(err))
+		return
+	}
+	*testBenchPath = filepath.Join(*testBenchPath, "keploy")
+
+	*configPath, err = getAbsolutePath(*configPath)
+	if err != nil {
+		logger.Error("failed to get absolute path", zap.String("path", *configPath), zap.Error(err))
+		return
+	}
+	println("ConfigPath:", *configPath)
+	// get all the sessions
+	tsets1, err := pkg.ReadSessionIndices(*preRecPath, logger)
+	if err != nil {
+		logger.Error("failed to read session indices", zap.String("path", *preRecPath), zap.Error(err))
+		return
+	}
+
+	tsets2, err := pkg.ReadSessionIndices(*testBenchPath, logger)
+	if err != nil {
+		logger.Error("failed to read session indices", zap.String("path", *testBenchPath), zap.Error(err))
+		return
+	}
+
+	// compare sessions, both should contain equal number of same sessions
+	if err := testbench.CompareSessions(tsets1, tsets2); err != nil {
+		logger.Error("sessions are not equal", zap.Error(err))
+		return
+	}
+
+	sessions := tsets1
+
+	if len(sessions) == 0 {
+		logger.Info("no sessions found")
+		return
+	}
+
+	// initialize the test dbs
+	db1 := testdb.New(logger, *preRecPath)
+	db2 := testdb.New(logger, *testBenchPath)
+
+	// Cancel ctx on Ctrl-C/SIGTERM so in-flight workers can stop between test
+	// cases instead of leaving mocks.yaml half-swapped.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *testAssert {
+		noise, err := testbench.GetNoiseFromConfig(logger, *configPath)
+		if err != nil {
+			if !errors.Is(err, testbench.ErrConfigUnmarshal) {
+				logger.Info("failed to get noise from config, continuing without config file", zap.Error(err))
+			} else {
+				logger.Error("failed to parse config file", zap.Error(err))
+			}
+		}
+
+		engine := testbench.NewEngine(logger, db1, db2, noise)
+		engine.Concurrency = *concurrency
+
+		// Run the test assertions
+		rep, err := engine.AssertTests(ctx, sessions)
+		if err != nil {
+			logger.Error("test case comparison aborted", zap.Error(err))
+		}
+		if reportErr := writeReport(rep, format, *reportOut); reportErr != nil {
+			logger.Error("failed to write report", zap.String("format", string(format)), zap.Error(reportErr))
+			if err == nil {
+				err = reportErr
+			}
+		}
+		if err != nil || !rep.Passed {
+			logger.Error("test cases are not equal")
+			os.Exit(1)
+		}
+		logger.Info("test cases are equal")
+		os.Exit(0)
+	} else if *mockAssert {
+		engine := testbench.NewEngine(logger, db1, db2, nil)
+		engine.Concurrency = *concurrency
+
+		// Prepare the mock assertions
+		if err := engine.PrepareMocks(ctx, sessions); err != nil {
+			logger.Error("failed to prepare mock assertions", zap.Error(err))
+			os.Exit(1)
+		}
+		logger.Info("mock assertions are prepared")
+		os.Exit(0)
+	} else if *bench {
+		// Run the latency-delta microbenchmark
+		regressed, err := runBench(ctx, logger, db1, db2, sessions, regressionThreshold, *benchCSV)
+		if err != nil {
+			logger.Error("failed to run bench", zap.Error(err))
+			os.Exit(1)
+		}
+		if regressed {
+			logger.Error("latency regression exceeds --regression-threshold", zap.String("threshold", *regressionThresholdFlag))
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+}
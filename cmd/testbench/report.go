@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+
+	"go.keploy.io/server/v2/pkg/models"
+
+	"testbench/pkg/testbench"
+)
+
+// reportFormat enumerates the supported --report-format values.
+type reportFormat string
+
+const (
+	reportFormatText  reportFormat = "text"
+	reportFormatJUnit reportFormat = "junit"
+	reportFormatJSON  reportFormat = "json"
+)
+
+// junitTestSuites mirrors the standard JUnit XML schema that CI consumers
+// (GitHub Actions, GitLab, Jenkins) already know how to render.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Data    string `xml:",chardata"`
+}
+
+// toJUnit converts the report into the JUnit XML document model.
+func toJUnit(r testbench.Report) junitTestSuites {
+	suites := junitTestSuites{}
+	for _, s := range r.Sessions {
+		suite := junitTestSuite{Name: s.Session, Tests: len(s.TestCases)}
+		for _, tc := range s.TestCases {
+			jtc := junitTestCase{Name: tc.Name}
+			if !tc.Passed {
+				suite.Failures++
+				diff := struct {
+					Req  *models.ReqCompare  `json:"req,omitempty"`
+					Resp *models.RespCompare `json:"resp,omitempty"`
+				}{tc.ReqDiff, tc.RespDiff}
+				data, _ := json.MarshalIndent(diff, "", "  ")
+				jtc.Failure = &junitFailure{
+					Message: "test case mismatch between pre-recorded and test-bench",
+					Data:    string(data),
+				}
+			}
+			suite.TestCases = append(suite.TestCases, jtc)
+		}
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+// printTextReport prints each failing test case's diff to stdout, the way
+// compareTestCases used to print as it went.
+func printTextReport(r testbench.Report) {
+	for _, s := range r.Sessions {
+		for _, tc := range s.TestCases {
+			if tc.Passed {
+				continue
+			}
+			switch {
+			case tc.ReqDiff != nil || tc.RespDiff != nil:
+				fmt.Printf("HttpReq diff:%v\n", tc.ReqDiff)
+				fmt.Printf("HttpResp diff:%v\n", tc.RespDiff)
+			case tc.Diff != "":
+				fmt.Printf("diff:%v\n", tc.Diff)
+			default:
+				fmt.Printf("session %s: test case %s failed\n", s.Session, tc.Name)
+			}
+		}
+	}
+}
+
+// writeReport renders the report in the requested format and writes it to
+// outPath. An empty outPath writes to stdout.
+func writeReport(r testbench.Report, format reportFormat, outPath string) error {
+	var data []byte
+	var err error
+
+	switch format {
+	case reportFormatJUnit:
+		data, err = xml.MarshalIndent(toJUnit(r), "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append([]byte(xml.Header), data...)
+	case reportFormatJSON:
+		data, err = json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+	case reportFormatText:
+		printTextReport(r)
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format: %q", format)
+	}
+
+	if outPath == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(outPath, data, 0644)
+}
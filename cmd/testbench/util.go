@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewDevelopmentLogger creates and returns a new zap logger for development use.
+// It takes a log level as a string (e.g., "debug", "info") and sets up the logger accordingly.
+func NewDevelopmentLogger(level string) (*zap.Logger, error) {
+	cfg := zap.NewDevelopmentConfig()
+
+	// Parse the string level to zap's atomic level
+	var lvl zapcore.Level
+	err := lvl.UnmarshalText([]byte(strings.ToLower(level)))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Level = zap.NewAtomicLevelAt(lvl)
+
+	// Build and return the logger
+	logger, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return logger, nil
+}
+
+func CheckFileExists(path string) bool {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return false
+	}
+	return true
+}
+
+func getAbsolutePath(path string) (string, error) {
+	//if user provides relative path
+	if len(path) > 0 && path[0] != '/' {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the absolute path from relative path:%v", err)
+		}
+		path = absPath
+	} else if len(path) == 0 { // if user doesn't provide any path
+		cdirPath, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get the path of current directory:%v", err)
+		}
+		path = cdirPath
+	}
+	// else if user provides absolute path, then return the same path
+	return path, nil
+}
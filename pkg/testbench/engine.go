@@ -0,0 +1,305 @@
+// Package testbench compares the test cases and mocks recorded by Keploy
+// against a pre-recorded baseline, so this logic can be embedded directly in
+// the main Keploy server's replay pipeline or in a user's Go integration
+// tests, instead of only being reachable by shelling out to cmd/testbench.
+package testbench
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/pkg/platform/yaml/mockdb"
+	testdb "go.keploy.io/server/v2/pkg/platform/yaml/testdb"
+	"go.keploy.io/server/v2/pkg/service/replay"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// Engine compares (or prepares mocks for) a pre-recorded test run against a
+// test-bench run, given the two sides' test databases.
+type Engine struct {
+	PreRecorded *testdb.TestYaml
+	TestBench   *testdb.TestYaml
+
+	// PreRecordedMocks and TestBenchMocks read the session-level mocks.yaml
+	// that a session's Postgres interactions live in. A test case's own
+	// Mocks field is never round-tripped through the test YAML, so Postgres
+	// mocks have to be looked up separately from the test cases, once per
+	// session rather than per test case (see sessionPostgresMocks).
+	PreRecordedMocks *mockdb.MockYaml
+	TestBenchMocks   *mockdb.MockYaml
+
+	Noise  *config.Globalnoise
+	Logger *zap.Logger
+
+	// Concurrency bounds how many sessions are compared/prepared at once.
+	// It defaults to runtime.GOMAXPROCS(0) when left at zero.
+	Concurrency int
+}
+
+// NewEngine builds an Engine from the two sides' test databases. noise may
+// be nil, in which case no fields are treated as noisy.
+func NewEngine(logger *zap.Logger, preRecorded, testBench *testdb.TestYaml, noise *config.Globalnoise) *Engine {
+	if noise == nil {
+		noise = &config.Globalnoise{}
+	}
+	return &Engine{
+		PreRecorded:      preRecorded,
+		TestBench:        testBench,
+		PreRecordedMocks: mockdb.New(logger, preRecorded.TcsPath, ""),
+		TestBenchMocks:   mockdb.New(logger, testBench.TcsPath, ""),
+		Noise:            noise,
+		Logger:           logger,
+		Concurrency:      runtime.GOMAXPROCS(0),
+	}
+}
+
+// sessionPostgresMocks loads every Postgres mock recorded for session. It
+// deliberately doesn't pass a time window to GetUnFilteredMocks: a session's
+// Postgres interactions are consumed dynamically by a live proxy as a test
+// run makes DB calls, not statically paired 1:1 with one HTTP test case, and
+// GetUnFilteredMocks returns its full mock set regardless of the window
+// anyway (the window only reorders which matches sort first). So Postgres
+// mocks are compared once per session instead of being (mis)attributed to
+// individual test cases.
+func sessionPostgresMocks(ctx context.Context, mockDB *mockdb.MockYaml, session string) ([]*models.Mock, error) {
+	mocks, err := mockDB.GetUnFilteredMocks(ctx, session, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	var pgMocks []*models.Mock
+	for _, m := range mocks {
+		if m.Kind == models.Postgres {
+			pgMocks = append(pgMocks, m)
+		}
+	}
+	return pgMocks, nil
+}
+
+func (e *Engine) concurrency() int {
+	if e.Concurrency < 1 {
+		return 1
+	}
+	return e.Concurrency
+}
+
+// AssertTests compares each session's pre-recorded and test-bench test
+// cases. Sessions are compared concurrently, bounded by e.Concurrency, the
+// same as before; the test cases within each session are compared
+// concurrently too, but they draw from a second pool also sized to
+// e.Concurrency and shared across every session, so a run with many
+// sessions each containing many test cases can't fan out to roughly
+// e.Concurrency^2 comparisons at once. ctx cancellation (e.g. on
+// SIGINT/SIGTERM) stops new work from starting. Sessions that finished
+// before a sibling errored still contribute their results to the returned
+// Report, so one bad session doesn't blank out everything else.
+func (e *Engine) AssertTests(ctx context.Context, sessions []string) (Report, error) {
+	sessReps := make([]SessionReport, len(sessions))
+	tcSem := semaphore.NewWeighted(int64(e.concurrency()))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.concurrency())
+
+	for i, session := range sessions {
+		i, session := i, session
+		g.Go(func() error {
+			sessRep, err := e.assertSessionTests(gctx, session, tcSem)
+			sessReps[i] = sessRep
+			return err
+		})
+	}
+
+	err := g.Wait()
+
+	// Build the report from whatever sessions finished even if one errored,
+	// so a single bad session doesn't blank out the results already computed
+	// for its siblings.
+	rep := Report{Sessions: sessReps, Passed: true}
+	for _, sessRep := range sessReps {
+		rep.Passed = rep.Passed && sessRep.Passed
+	}
+	return rep, err
+}
+
+// assertSessionTests compares a single session's test cases, returning an
+// error for the failure modes that should abort the whole run, so errgroup
+// cancels the sibling sessions too. tcSem is shared with every other
+// session's test-case comparisons (see AssertTests) so their combined
+// concurrency never exceeds e.Concurrency.
+func (e *Engine) assertSessionTests(ctx context.Context, session string, tcSem *semaphore.Weighted) (SessionReport, error) {
+	e.Logger.Info("comparing session", zap.String("session", session))
+
+	noiseConfig := e.Noise.Global
+	if tsNoise, ok := e.Noise.Testsets[session]; ok {
+		noiseConfig = replay.LeftJoinNoise(e.Noise.Global, tsNoise)
+	}
+
+	readTcs1, err := e.PreRecorded.GetTestCases(ctx, session)
+	if err != nil {
+		return SessionReport{Session: session}, fmt.Errorf("session %s: failed to get pre-recorded test cases: %w", session, err)
+	}
+	sort.Slice(readTcs1, func(i, j int) bool { return readTcs1[i].Name < readTcs1[j].Name })
+
+	readTcs2, err := e.TestBench.GetTestCases(ctx, session)
+	if err != nil {
+		return SessionReport{Session: session}, fmt.Errorf("session %s: failed to get test-bench test cases: %w", session, err)
+	}
+	sort.Slice(readTcs2, func(i, j int) bool { return readTcs2[i].Name < readTcs2[j].Name })
+
+	if len(readTcs1) != len(readTcs2) {
+		return SessionReport{Session: session}, fmt.Errorf("%w: session %s: pre-recorded has %d, test-bench has %d", ErrTestCaseCountMismatch, session, len(readTcs1), len(readTcs2))
+	}
+
+	sessRep := SessionReport{Session: session, Passed: true, Noise: noiseConfig}
+
+	// Test cases within a session are compared concurrently too, drawing
+	// from the same tcSem as every other session's test cases, since each
+	// pair's comparison is independent of its siblings. g also caps how many
+	// of this session's goroutines are outstanding at once (rather than
+	// spawning all len(readTcs1) of them immediately to block on tcSem), so
+	// a session with a huge number of test cases doesn't balloon goroutine
+	// count on its own.
+	tcResults := make([]TestCaseResult, len(readTcs1))
+	tcDone := make([]bool, len(readTcs1))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.concurrency())
+	for i := 0; i < len(readTcs1); i++ {
+		i := i
+		g.Go(func() error {
+			if err := tcSem.Acquire(gctx, 1); err != nil {
+				return err
+			}
+			defer tcSem.Release(1)
+
+			asserter := protocolAsserterFor(readTcs1[i].Kind)
+			ok, diff := asserter.Compare(readTcs1[i], readTcs2[i], noiseConfig, e.Logger)
+			tcRes := TestCaseResult{Name: readTcs1[i].Name, Passed: ok}
+			if !ok {
+				e.Logger.Error("tests are different", zap.String("pre-recorded", readTcs1[i].Name), zap.String("test-bench", readTcs2[i].Name), zap.Any("diff", diff))
+				if absRes, isHTTP := diff.(*models.AbsResult); isHTTP {
+					tcRes.ReqDiff = &absRes.Req
+					tcRes.RespDiff = &absRes.Resp
+				} else {
+					tcRes.Diff = fmt.Sprintf("%v", diff)
+				}
+			}
+			tcResults[i] = tcRes
+			tcDone[i] = true
+			return nil
+		})
+	}
+	waitErr := g.Wait()
+
+	// Keep whatever test cases did finish even if the group was cancelled
+	// partway through, so an aborted session still reports the work it
+	// completed instead of silently claiming to have passed with none.
+	// tcDone (rather than a zero-value check on tcRes) is what distinguishes
+	// a finished comparison from a slot the cancellation never reached, so a
+	// legitimately empty-named test case can't be mistaken for one.
+	for i, tcRes := range tcResults {
+		if !tcDone[i] {
+			continue
+		}
+		sessRep.TestCases = append(sessRep.TestCases, tcRes)
+		sessRep.Passed = sessRep.Passed && tcRes.Passed
+	}
+	if waitErr != nil {
+		sessRep.Passed = false
+		return sessRep, waitErr
+	}
+
+	pgMocksA, err := sessionPostgresMocks(ctx, e.PreRecordedMocks, session)
+	if err != nil {
+		return sessRep, fmt.Errorf("session %s: failed to get pre-recorded postgres mocks: %w", session, err)
+	}
+	pgMocksB, err := sessionPostgresMocks(ctx, e.TestBenchMocks, session)
+	if err != nil {
+		return sessRep, fmt.Errorf("session %s: failed to get test-bench postgres mocks: %w", session, err)
+	}
+	if len(pgMocksA) > 0 || len(pgMocksB) > 0 {
+		ok, diff := comparePostgresMocks(pgMocksA, pgMocksB, noiseConfig, e.Logger)
+		tcRes := TestCaseResult{Name: "postgres mocks", Passed: ok}
+		if !ok {
+			e.Logger.Error("postgres mocks are different", zap.String("session", session), zap.Any("diff", diff))
+			tcRes.Diff = fmt.Sprintf("%v", diff)
+		}
+		sessRep.TestCases = append(sessRep.TestCases, tcRes)
+		sessRep.Passed = sessRep.Passed && ok
+	}
+	return sessRep, nil
+}
+
+// PrepareMocks swaps timestamps and mocks between the pre-recorded and
+// test-bench sides for every session. Sessions run concurrently, bounded by
+// e.Concurrency; a cancelled ctx is checked between test cases so a
+// Ctrl-C/SIGTERM stops new swaps instead of racing with swapFiles.
+func (e *Engine) PrepareMocks(ctx context.Context, sessions []string) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.concurrency())
+
+	for _, session := range sessions {
+		session := session
+		g.Go(func() error {
+			return e.prepareSessionMocks(gctx, session)
+		})
+	}
+
+	return g.Wait()
+}
+
+func (e *Engine) prepareSessionMocks(ctx context.Context, session string) error {
+	readTcs1, err := e.PreRecorded.GetTestCases(ctx, session)
+	if err != nil {
+		return fmt.Errorf("session %s: failed to get pre-recorded test cases: %w", session, err)
+	}
+	sort.Slice(readTcs1, func(i, j int) bool { return readTcs1[i].Name < readTcs1[j].Name })
+
+	readTcs2, err := e.TestBench.GetTestCases(ctx, session)
+	if err != nil {
+		return fmt.Errorf("session %s: failed to get test-bench test cases: %w", session, err)
+	}
+	sort.Slice(readTcs2, func(i, j int) bool { return readTcs2[i].Name < readTcs2[j].Name })
+
+	if len(readTcs1) != len(readTcs2) {
+		return fmt.Errorf("%w: session %s: pre-recorded has %d, test-bench has %d", ErrTestCaseCountMismatch, session, len(readTcs1), len(readTcs2))
+	}
+
+	for i := 0; i < len(readTcs1); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if readTcs1[i].Name != readTcs2[i].Name {
+			return fmt.Errorf("%w: session %s: %q vs %q", ErrTestCaseNameMismatch, session, readTcs1[i].Name, readTcs2[i].Name)
+		}
+
+		// Swap timestamps using the protocol-appropriate asserter. Postgres
+		// mocks aren't paired with a test case at all, so they need no
+		// per-test-case swap here: swapFiles below swaps the whole session's
+		// mocks.yaml wholesale.
+		asserter := protocolAsserterFor(readTcs1[i].Kind)
+		asserter.SwapTimestamps(readTcs1[i], readTcs2[i])
+
+		if err := e.PreRecorded.UpdateTestCase(ctx, readTcs1[i], session); err != nil {
+			return fmt.Errorf("session %s: failed to update pre-recorded test case %s: %w", session, readTcs1[i].Name, err)
+		}
+		if err := e.TestBench.UpdateTestCase(ctx, readTcs2[i], session); err != nil {
+			return fmt.Errorf("session %s: failed to update test-bench test case %s: %w", session, readTcs2[i].Name, err)
+		}
+	}
+
+	if err := swapFiles(filepath.Join(e.PreRecorded.TcsPath, session, "mocks.yaml"), filepath.Join(e.TestBench.TcsPath, session, "mocks.yaml")); err != nil {
+		return fmt.Errorf("session %s: failed to swap mock files: %w", session, err)
+	}
+
+	return nil
+}
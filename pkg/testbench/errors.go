@@ -0,0 +1,23 @@
+package testbench
+
+import "errors"
+
+// Sentinel errors returned by Engine, so callers can branch on failure mode
+// with errors.Is instead of matching on log messages.
+var (
+	// ErrSessionCountMismatch is returned when the pre-recorded and
+	// test-bench sides don't contain the same set of sessions.
+	ErrSessionCountMismatch = errors.New("pre-recorded and test-bench sessions are not equal")
+
+	// ErrTestCaseCountMismatch is returned when a session has a different
+	// number of test cases on each side.
+	ErrTestCaseCountMismatch = errors.New("pre-recorded and test-bench test case counts are not equal")
+
+	// ErrTestCaseNameMismatch is returned when the sorted test case names
+	// within a session don't line up between the two sides.
+	ErrTestCaseNameMismatch = errors.New("pre-recorded and test-bench test case names are not equal")
+
+	// ErrConfigUnmarshal is returned when the keploy noise config exists
+	// but can't be unmarshalled.
+	ErrConfigUnmarshal = errors.New("failed to unmarshal the keploy noise config")
+)
@@ -0,0 +1,35 @@
+package testbench
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"go.keploy.io/server/v2/config"
+	"go.uber.org/zap"
+)
+
+// GetNoiseFromConfig reads the keploy.yml noise config from configPath. A
+// missing config file is not an error: it returns an empty GlobalNoise so
+// callers can compare tests without any fields excluded.
+func GetNoiseFromConfig(logger *zap.Logger, configPath string) (*config.Globalnoise, error) {
+	var cfg *config.Config = &config.Config{}
+
+	viper.SetConfigName("keploy")
+	viper.SetConfigType("yml")
+	viper.AddConfigPath(configPath)
+	if err := viper.ReadInConfig(); err != nil {
+		var configFileNotFoundError viper.ConfigFileNotFoundError
+		if !errors.As(err, &configFileNotFoundError) {
+			return &cfg.Test.GlobalNoise, fmt.Errorf("failed to read config file: %w", err)
+		}
+		logger.Info("config file not found", zap.String("configPath", configPath))
+		return &cfg.Test.GlobalNoise, nil
+	}
+
+	if err := viper.Unmarshal(cfg); err != nil {
+		return &cfg.Test.GlobalNoise, fmt.Errorf("%w: %v", ErrConfigUnmarshal, err)
+	}
+
+	return &cfg.Test.GlobalNoise, nil
+}
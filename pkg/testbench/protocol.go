@@ -0,0 +1,247 @@
+package testbench
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgproto3/v2"
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+	"go.keploy.io/server/v2/pkg/service/replay"
+	"go.uber.org/zap"
+)
+
+// ProtocolAsserter compares two test cases captured for the same protocol
+// and swaps their pre-recorded/test-bench timestamps in place. Dispatching
+// on it lets compareTestCases and prepareMockAssertion treat every protocol
+// Keploy records the same way, instead of only ever diffing HTTPReq/HTTPResp
+// and silently passing everything else.
+type ProtocolAsserter interface {
+	// Compare reports whether tcA and tcB represent the same interaction,
+	// modulo the supplied noise config. diff is protocol-specific and only
+	// meaningful when ok is false.
+	Compare(tcA, tcB *models.TestCase, noise config.GlobalNoise, logger *zap.Logger) (ok bool, diff any)
+	// SwapTimestamps exchanges the request/response timestamps recorded for
+	// tcA and tcB, mirroring what prepareMockAssertion already does for HTTP.
+	SwapTimestamps(tcA, tcB *models.TestCase)
+}
+
+// protocolAsserterFor dispatches on a test case's Kind so that HTTP and gRPC
+// test cases are each compared with the right protocol semantics. Postgres
+// is not a top-level test case Kind and isn't handled here: a session's
+// Postgres interactions live in its mocks.yaml rather than being paired with
+// a specific test case, so Engine compares them once per session instead
+// (see comparePostgresMocks).
+func protocolAsserterFor(kind models.Kind) ProtocolAsserter {
+	switch kind {
+	case models.GRPC_EXPORT:
+		return grpcAsserter{}
+	default:
+		return httpAsserter{}
+	}
+}
+
+// swapHTTPTimestamps is shared by every asserter: regardless of protocol, a
+// test case's pairing/ordering timestamp always lives on HTTPReq/HTTPResp
+// (see testdb.TestYaml.GetTestCases, which sorts on HTTPReq.Timestamp).
+func swapHTTPTimestamps(tcA, tcB *models.TestCase) {
+	tcA.HTTPReq.Timestamp, tcB.HTTPReq.Timestamp = tcB.HTTPReq.Timestamp, tcA.HTTPReq.Timestamp
+	tcA.HTTPResp.Timestamp, tcB.HTTPResp.Timestamp = tcB.HTTPResp.Timestamp, tcA.HTTPResp.Timestamp
+}
+
+type httpAsserter struct{}
+
+func (httpAsserter) Compare(tcA, tcB *models.TestCase, noise config.GlobalNoise, logger *zap.Logger) (bool, any) {
+	ok, _, _, absRes := replay.AbsMatch(tcA, tcB, noise, true, logger)
+	return ok, absRes
+}
+
+func (httpAsserter) SwapTimestamps(tcA, tcB *models.TestCase) {
+	swapHTTPTimestamps(tcA, tcB)
+}
+
+// grpcDiff is returned by grpcAsserter.Compare when a pair of gRPC test
+// cases don't match, so the caller can render the expected/actual payloads.
+type grpcDiff struct {
+	ReqEqual     bool            `json:"req_equal"`
+	RespEqual    bool            `json:"resp_equal"`
+	ExpectedReq  models.GrpcReq  `json:"expected_req"`
+	ActualReq    models.GrpcReq  `json:"actual_req"`
+	ExpectedResp models.GrpcResp `json:"expected_resp"`
+	ActualResp   models.GrpcResp `json:"actual_resp"`
+}
+
+type grpcAsserter struct{}
+
+func (grpcAsserter) Compare(tcA, tcB *models.TestCase, noise config.GlobalNoise, _ *zap.Logger) (bool, any) {
+	headerNoise := noise["header"]
+
+	reqEqual := headersMatch(tcA.GrpcReq.Headers, tcB.GrpcReq.Headers, headerNoise) &&
+		tcA.GrpcReq.Body.DecodedData == tcB.GrpcReq.Body.DecodedData
+
+	respEqual := headersMatch(tcA.GrpcResp.Headers, tcB.GrpcResp.Headers, headerNoise) &&
+		headersMatch(tcA.GrpcResp.Trailers, tcB.GrpcResp.Trailers, headerNoise) &&
+		tcA.GrpcResp.Body.DecodedData == tcB.GrpcResp.Body.DecodedData
+
+	diff := grpcDiff{
+		ReqEqual:     reqEqual,
+		RespEqual:    respEqual,
+		ExpectedReq:  tcA.GrpcReq,
+		ActualReq:    tcB.GrpcReq,
+		ExpectedResp: tcA.GrpcResp,
+		ActualResp:   tcB.GrpcResp,
+	}
+	return reqEqual && respEqual, diff
+}
+
+func (grpcAsserter) SwapTimestamps(tcA, tcB *models.TestCase) {
+	swapHTTPTimestamps(tcA, tcB)
+}
+
+// headersMatch compares pseudo/ordinary gRPC headers, ignoring any key
+// present in noise (the same "ignore this field" convention replay.AbsMatch
+// uses for HTTP headers).
+func headersMatch(a, b models.GrpcHeaders, noise map[string][]string) bool {
+	return headerMapMatches(a.PseudoHeaders, b.PseudoHeaders, noise) &&
+		headerMapMatches(a.OrdinaryHeaders, b.OrdinaryHeaders, noise)
+}
+
+func headerMapMatches(a, b map[string]string, noise map[string][]string) bool {
+	for k, v := range a {
+		if _, ignored := noise[k]; ignored {
+			continue
+		}
+		if b[k] != v {
+			return false
+		}
+	}
+	for k := range b {
+		if _, ignored := noise[k]; ignored {
+			continue
+		}
+		if _, ok := a[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// postgresDiff is returned by comparePostgresMocks when a pair of Postgres
+// mocks recorded for a session don't match.
+type postgresDiff struct {
+	Mock         string           `json:"mock"`
+	ReqEqual     bool             `json:"req_equal"`
+	RespEqual    bool             `json:"resp_equal"`
+	ExpectedReqs []models.Backend `json:"expected_requests"`
+	ActualReqs   []models.Backend `json:"actual_requests"`
+}
+
+// comparePostgresMocks diffs a session's pre-recorded and test-bench
+// Postgres mocks at the message level, ignoring the generated cursor/portal
+// names and bind parameter encodings called out in the "bind" noise
+// category, since those differ run-to-run even when two captures represent
+// the same query. Called once per session by Engine, rather than per test
+// case: a session's Postgres mocks live in its mocks.yaml independent of
+// any particular HTTP test case, the same way Keploy's own replay matcher
+// resolves them against the live proxy rather than a fixed 1:1 pairing.
+func comparePostgresMocks(mocksA, mocksB []*models.Mock, noise config.GlobalNoise, logger *zap.Logger) (bool, *postgresDiff) {
+	bindNoise := noise["bind"]
+
+	if len(mocksA) != len(mocksB) {
+		logger.Debug("number of postgres mocks differ", zap.Int("expected", len(mocksA)), zap.Int("actual", len(mocksB)))
+		return false, &postgresDiff{ReqEqual: false, RespEqual: false}
+	}
+
+	for i := range mocksA {
+		reqEqual := backendsEqual(mocksA[i].Spec.PostgresRequests, mocksB[i].Spec.PostgresRequests, bindNoise)
+		respEqual := frontendsEqual(mocksA[i].Spec.PostgresResponses, mocksB[i].Spec.PostgresResponses, bindNoise)
+		if !reqEqual || !respEqual {
+			return false, &postgresDiff{
+				Mock:         mocksA[i].Name,
+				ReqEqual:     reqEqual,
+				RespEqual:    respEqual,
+				ExpectedReqs: mocksA[i].Spec.PostgresRequests,
+				ActualReqs:   mocksB[i].Spec.PostgresRequests,
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func backendsEqual(a, b []models.Backend, bindNoise map[string][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		reqA, reqB := a[i], b[i]
+		if reqA.Identfier != reqB.Identfier {
+			return false
+		}
+		// Bind is a transient scratch field (yaml:"-") never round-tripped
+		// through the mock YAML; Binds is what GetTestCases actually reads
+		// back, so that's what has to be compared.
+		if !bindsEqual(reqA.Binds, reqB.Binds, bindNoise) {
+			return false
+		}
+		// Payload is the raw frame bytes, which still embed the bind
+		// parameter encodings the Binds comparison above already accounted
+		// for noise on. Identfier is only ever "StartupRequest",
+		// "ClientRequest" or "ServerResponse" (see postgres/v1/encode.go) -
+		// the single-letter protocol codes like "B" for Bind live in
+		// PacketTypes, so that's what has to be checked to skip the raw
+		// Payload comparison for pipelined frames containing a Bind message.
+		if !containsPacketType(reqA.PacketTypes, "B") && reqA.Payload != reqB.Payload {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPacketType(packetTypes []string, t string) bool {
+	for _, pt := range packetTypes {
+		if pt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// bindsEqual compares a Backend frame's pipelined Bind messages, ignoring
+// the portal/statement names called out in bindNoise since those are
+// generated cursor/portal identifiers that differ run-to-run even when two
+// captures represent the same query.
+func bindsEqual(a, b []pgproto3.Bind, bindNoise map[string][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	_, ignorePortal := bindNoise["portal"]
+	_, ignoreStatement := bindNoise["statement"]
+	for i := range a {
+		bindA, bindB := a[i], b[i]
+		if ignorePortal {
+			bindA.DestinationPortal, bindB.DestinationPortal = "", ""
+		}
+		if ignoreStatement {
+			bindA.PreparedStatement, bindB.PreparedStatement = "", ""
+		}
+		if !reflect.DeepEqual(bindA, bindB) {
+			return false
+		}
+	}
+	return true
+}
+
+func frontendsEqual(a, b []models.Frontend, _ map[string][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Identfier != b[i].Identfier {
+			return false
+		}
+		if a[i].Payload != b[i].Payload {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package testbench
+
+import (
+	"go.keploy.io/server/v2/config"
+	"go.keploy.io/server/v2/pkg/models"
+)
+
+// TestCaseResult holds the outcome of comparing a single pair of test cases
+// within a session, along with the diff that produced a failure (if any).
+type TestCaseResult struct {
+	Name     string              `json:"name"`
+	Passed   bool                `json:"passed"`
+	ReqDiff  *models.ReqCompare  `json:"req_diff,omitempty"`
+	RespDiff *models.RespCompare `json:"resp_diff,omitempty"`
+	// Diff holds the raw diff for non-HTTP kinds (gRPC, Postgres), which
+	// don't have a typed ReqCompare/RespCompare breakdown.
+	Diff string `json:"diff,omitempty"`
+}
+
+// SessionReport aggregates the per-testcase results of a single session.
+type SessionReport struct {
+	Session string `json:"session"`
+	Passed  bool   `json:"passed"`
+	// Noise is the noise config that was actually applied when comparing
+	// this session (e.g.Global left-joined with the session's Testsets
+	// override), so the report is self-contained without needing the
+	// original noise config file to interpret a pass/fail.
+	Noise     config.GlobalNoise `json:"noise,omitempty"`
+	TestCases []TestCaseResult   `json:"testcases"`
+}
+
+// Report is the overall result of an AssertTests run. Callers can render it
+// however they like (e.g. the cmd/testbench wrapper renders text, JUnit XML
+// or JSON).
+type Report struct {
+	Passed   bool            `json:"passed"`
+	Sessions []SessionReport `json:"sessions"`
+}
@@ -0,0 +1,26 @@
+package testbench
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CompareSessions checks that the pre-recorded and test-bench sides contain
+// the same set of session names, regardless of order.
+func CompareSessions(preRecorded, testBench []string) error {
+	if len(preRecorded) != len(testBench) {
+		return fmt.Errorf("%w: pre-recorded has %d, test-bench has %d", ErrSessionCountMismatch, len(preRecorded), len(testBench))
+	}
+
+	sorted1 := append([]string(nil), preRecorded...)
+	sorted2 := append([]string(nil), testBench...)
+	sort.Strings(sorted1)
+	sort.Strings(sorted2)
+
+	for i := range sorted1 {
+		if sorted1[i] != sorted2[i] {
+			return fmt.Errorf("%w: pre-recorded has %q, test-bench has %q", ErrSessionCountMismatch, sorted1[i], sorted2[i])
+		}
+	}
+	return nil
+}
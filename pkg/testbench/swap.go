@@ -0,0 +1,44 @@
+package testbench
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+// swapFiles exchanges the contents of file1Path and file2Path.
+func swapFiles(file1Path, file2Path string) error {
+	// Read content from both files before writing anything, so a missing
+	// file fails before either side is touched.
+	content1, err := ioutil.ReadFile(file1Path)
+	if err != nil {
+		return err
+	}
+
+	content2, err := ioutil.ReadFile(file2Path)
+	if err != nil {
+		return err
+	}
+
+	// Write each side to a temp file and rename into place, so a Ctrl-C (or
+	// crash) mid-swap can't leave one file truncated while the other still
+	// holds its original content.
+	if err := writeFileAtomic(file1Path, content2); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(file2Path, content1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to path by first writing it to "path.tmp" in
+// the same directory and renaming it over path, so readers never observe a
+// partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0777); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}